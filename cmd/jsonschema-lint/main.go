@@ -0,0 +1,76 @@
+// Command jsonschema-lint validates JSON/YAML config files against a JSON
+// Schema, so a project that publishes a schema for its config format (compose
+// files, CI pipeline files, Helm-style values) gets a zero-setup linter for
+// contributions.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/drycc/jsonschema"
+)
+
+func main() {
+	format := flag.String("format", "text", "output format: text|json|github")
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) < 2 {
+		fmt.Fprintln(os.Stderr, "usage: jsonschema-lint [--format=text|json|github] <schema> <instance>...")
+		os.Exit(2)
+	}
+
+	results, err := jsonschema.Lint(args[0], args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	if printResults(results, *format) {
+		os.Exit(1)
+	}
+}
+
+// printResults renders results in the requested format and reports whether
+// any instance failed validation.
+func printResults(results []jsonschema.LintResult, format string) bool {
+	failed := false
+	for _, r := range results {
+		if len(r.Errors) > 0 {
+			failed = true
+		}
+	}
+
+	switch format {
+	case "json":
+		json.NewEncoder(os.Stdout).Encode(results)
+	case "github":
+		for _, r := range results {
+			for _, e := range r.Errors {
+				// Lines is only populated for YAML instances (yaml.v3 keeps
+				// node positions); JSON instances fall back to line 1, since
+				// encoding/json discards them.
+				line := 1
+				if l, ok := r.Lines[e.Field]; ok {
+					line = l
+				}
+				fmt.Printf("::error file=%s,line=%d::%s: %s\n", r.Path, line, e.Field, e.Description)
+			}
+		}
+	default:
+		for _, r := range results {
+			if len(r.Errors) == 0 {
+				fmt.Printf("%s: OK\n", r.Path)
+				continue
+			}
+			fmt.Printf("%s:\n", r.Path)
+			for _, e := range r.Errors {
+				fmt.Printf("  %s: %s\n", e.Field, e.Description)
+			}
+		}
+	}
+	return failed
+}
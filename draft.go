@@ -0,0 +1,46 @@
+package jsonschema
+
+// Draft identifies which JSON Schema draft a document is validated against.
+// Newer keywords (e.g. "propertyNames", "if"/"then"/"else",
+// "dependentRequired"/"dependentSchemas") are only registered for schemas
+// whose Draft is new enough to define them; set Schema.Draft to lock a
+// document to a specific draft instead of relying on auto-detection.
+type Draft int
+
+const (
+	Draft4 Draft = iota
+	Draft6
+	Draft7
+	Draft2019_09
+)
+
+// Minimum draft each new-style keyword in this package requires. The
+// node-registration switch consults these when deciding which validators to
+// build for a schema, so that e.g. a Draft4-locked schema ignores a stray
+// "propertyNames" key instead of enforcing it.
+const (
+	propertyNamesMinDraft     = Draft6
+	containsMinDraft          = Draft6
+	constMinDraft             = Draft6
+	ifThenElseMinDraft        = Draft7
+	dependentKeywordsMinDraft = Draft2019_09
+)
+
+// draftFromSchemaURI maps a "$schema" value to the Draft it declares. ok is
+// false for unrecognized or missing URIs, in which case callers should fall
+// back to the schema's explicit Draft field (or Draft7, the historical
+// default of this package).
+func draftFromSchemaURI(uri string) (Draft, bool) {
+	switch uri {
+	case "http://json-schema.org/schema#",
+		"http://json-schema.org/draft-04/schema#":
+		return Draft4, true
+	case "http://json-schema.org/draft-06/schema#":
+		return Draft6, true
+	case "http://json-schema.org/draft-07/schema#":
+		return Draft7, true
+	case "https://json-schema.org/draft/2019-09/schema":
+		return Draft2019_09, true
+	}
+	return Draft7, false
+}
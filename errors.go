@@ -0,0 +1,159 @@
+package jsonschema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidationError describes a single failure to validate an instance against
+// a schema. Field and SchemaField are JSON Pointers (RFC 6901) into the
+// instance and the schema document respectively, Type is a stable error
+// code (e.g. "required", "additional_property_not_allowed") that callers can
+// switch on instead of parsing Description, and Details carries the values
+// that were interpolated into Description (e.g. "property", "min", "max").
+type ValidationError struct {
+	Field       string                 `json:"field"`
+	SchemaField string                 `json:"schemaField"`
+	Type        string                 `json:"type"`
+	Details     map[string]interface{} `json:"details,omitempty"`
+	Description string                 `json:"description"`
+}
+
+// Error implements the error interface so existing callers that treated
+// ValidationError as a plain string-producing error keep working.
+func (e ValidationError) Error() string {
+	return e.Description
+}
+
+// ValidationContext accumulates the instance and schema JSON Pointers as
+// validation recurses into child schemas, and carries the Locale used to
+// render error descriptions. It's created once per top-level Schema.Validate
+// call and passed down through every Validator.Validate call.
+type ValidationContext struct {
+	instancePath []string
+	schemaPath   []string
+	locale       Locale
+}
+
+// newValidationContext returns the root context for a validation run. A nil
+// locale falls back to EnglishLocale.
+func newValidationContext(locale Locale) *ValidationContext {
+	if locale == nil {
+		locale = EnglishLocale{}
+	}
+	return &ValidationContext{locale: locale}
+}
+
+// descend returns a copy of c with instanceToks appended to the instance
+// pointer and schemaToks appended to the schema pointer. Either may be nil
+// when a keyword doesn't advance that pointer (e.g. "required" never
+// descends into the instance, since the property it complains about is
+// absent).
+func (c *ValidationContext) descend(instanceToks, schemaToks []string) *ValidationContext {
+	return &ValidationContext{
+		instancePath: append(append([]string{}, c.instancePath...), instanceToks...),
+		schemaPath:   append(append([]string{}, c.schemaPath...), schemaToks...),
+		locale:       c.locale,
+	}
+}
+
+func (c *ValidationContext) fieldPointer() string {
+	return pointerString(c.instancePath)
+}
+
+func (c *ValidationContext) schemaFieldPointer() string {
+	return pointerString(c.schemaPath)
+}
+
+// newError builds a ValidationError anchored at c's current instance and
+// schema pointers, rendering tpl (one of the Locale format strings) against
+// details.
+func (c *ValidationContext) newError(errType, tpl string, details map[string]interface{}) ValidationError {
+	return ValidationError{
+		Field:       c.fieldPointer(),
+		SchemaField: c.schemaFieldPointer(),
+		Type:        errType,
+		Details:     details,
+		Description: renderTemplate(tpl, details),
+	}
+}
+
+func pointerString(toks []string) string {
+	if len(toks) == 0 {
+		return ""
+	}
+	escaped := make([]string, len(toks))
+	for i, t := range toks {
+		escaped[i] = escapePointerToken(t)
+	}
+	return "/" + strings.Join(escaped, "/")
+}
+
+// escapePointerToken encodes a single RFC 6901 pointer segment, the inverse
+// of unescapeToken in reference.go: "~" must be escaped before "/", since
+// escaping "/" first would turn the "~" of the "~1" it produced into "~01".
+func escapePointerToken(tok string) string {
+	tok = strings.ReplaceAll(tok, "~", "~0")
+	tok = strings.ReplaceAll(tok, "/", "~1")
+	return tok
+}
+
+// renderTemplate fills the "{{key}}" placeholders in tpl from details.
+func renderTemplate(tpl string, details map[string]interface{}) string {
+	for k, v := range details {
+		tpl = strings.ReplaceAll(tpl, "{{"+k+"}}", fmt.Sprintf("%v", v))
+	}
+	return tpl
+}
+
+// Locale supplies one format string per ValidationError Type listed below, so
+// error descriptions can be translated without touching validator
+// implementations. Placeholders in the returned string are filled from the
+// matching ValidationError's Details, e.g. "{{property}}". The "format" Type
+// (and Lint's "parse_error") has no Locale method: its description is always
+// in English.
+type Locale interface {
+	Required() string
+	AdditionalPropertyNotAllowed() string
+	DependencyRequiredProperty() string
+	MaxProperties() string
+	MinProperties() string
+	Const() string
+	Contains() string
+	MaxContains() string
+}
+
+// EnglishLocale is the default Locale.
+type EnglishLocale struct{}
+
+func (EnglishLocale) Required() string {
+	return "{{property}} is required"
+}
+
+func (EnglishLocale) AdditionalPropertyNotAllowed() string {
+	return "Additional property {{property}} is not allowed"
+}
+
+func (EnglishLocale) DependencyRequiredProperty() string {
+	return "Must have property {{property}} when {{dependency}} is present"
+}
+
+func (EnglishLocale) MaxProperties() string {
+	return "Object has more properties than maxProperties ({{given}} > {{max}})"
+}
+
+func (EnglishLocale) MinProperties() string {
+	return "Object has fewer properties than minProperties ({{given}} < {{min}})"
+}
+
+func (EnglishLocale) Const() string {
+	return "{{given}} does not equal const value"
+}
+
+func (EnglishLocale) Contains() string {
+	return "Array must contain at least {{min}} matching item(s), contains {{given}}"
+}
+
+func (EnglishLocale) MaxContains() string {
+	return "Array must contain at most {{max}} matching item(s), contains {{given}}"
+}
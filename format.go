@@ -0,0 +1,234 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// FormatChecker reports whether a decoded instance value satisfies a named
+// "format" keyword. Implementations receive the raw decoded value (string,
+// float64, bool, ...) rather than a pre-cast string, so that checkers for
+// numeric formats (e.g. int32/int64 ranges) are possible alongside the
+// string-based ones defined by the JSON Schema spec.
+type FormatChecker interface {
+	IsFormat(input interface{}) bool
+}
+
+// FormatCheckerChain is a registry of named FormatCheckers. It's safe for
+// concurrent use, since schemas may be validated concurrently with new
+// formats being registered.
+type FormatCheckerChain struct {
+	mu       sync.RWMutex
+	checkers map[string]FormatChecker
+}
+
+// Formats is the process-wide registry consulted by the "format" validator.
+// Downstream projects register domain-specific formats (e.g. "ports" for a
+// Kubernetes manifest) here.
+var Formats = newFormatCheckerChain()
+
+func newFormatCheckerChain() *FormatCheckerChain {
+	c := &FormatCheckerChain{checkers: make(map[string]FormatChecker)}
+	c.Add("date-time", formatCheckerFunc(isDateTime))
+	c.Add("date", formatCheckerFunc(isDate))
+	c.Add("time", formatCheckerFunc(isTime))
+	c.Add("email", formatCheckerFunc(isEmail))
+	c.Add("hostname", formatCheckerFunc(isHostname))
+	c.Add("ipv4", formatCheckerFunc(isIPv4))
+	c.Add("ipv6", formatCheckerFunc(isIPv6))
+	c.Add("uri", formatCheckerFunc(isURI))
+	c.Add("uri-reference", formatCheckerFunc(isURIReference))
+	c.Add("uuid", formatCheckerFunc(isUUID))
+	c.Add("regex", formatCheckerFunc(isRegex))
+	c.Add("duration", formatCheckerFunc(isDuration))
+	return c
+}
+
+// Add registers a FormatChecker under name, replacing any existing checker
+// with that name.
+func (c *FormatCheckerChain) Add(name string, checker FormatChecker) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checkers[name] = checker
+}
+
+// Remove unregisters the checker for name, if any.
+func (c *FormatCheckerChain) Remove(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.checkers, name)
+}
+
+// Has reports whether a checker is registered under name.
+func (c *FormatCheckerChain) Has(name string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	_, ok := c.checkers[name]
+	return ok
+}
+
+func (c *FormatCheckerChain) get(name string) (FormatChecker, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	checker, ok := c.checkers[name]
+	return checker, ok
+}
+
+// formatCheckerFunc adapts a plain function to the FormatChecker interface.
+type formatCheckerFunc func(input interface{}) bool
+
+func (f formatCheckerFunc) IsFormat(input interface{}) bool {
+	return f(input)
+}
+
+// format is the "format" validator. Unknown format names and type mismatches
+// (e.g. "format" on a non-string instance where the checker only accepts
+// strings) are not errors. A value that fails its format check is not
+// reported at all, neither as an error nor as a warning, unless the owning
+// Schema opts in via AssertFormat.
+type format struct {
+	name   string
+	schema *Schema
+}
+
+func (f format) Validate(v interface{}, ctx *ValidationContext) []ValidationError {
+	checker, ok := Formats.get(f.name)
+	if !ok {
+		return nil
+	}
+	if checker.IsFormat(v) {
+		return nil
+	}
+	if f.schema == nil || !f.schema.AssertFormat {
+		return nil
+	}
+	nested := ctx.descend(nil, []string{"format"})
+	return []ValidationError{nested.newError("format", fmt.Sprintf("{{given}} is not valid against format %q", f.name),
+		map[string]interface{}{"given": v})}
+}
+
+func (f *format) UnmarshalJSON(b []byte) error {
+	return json.Unmarshal(b, &f.name)
+}
+
+// SetSchema gives the format validator a handle back to its owning Schema,
+// so Validate can check the AssertFormat option.
+func (f *format) SetSchema(s *Schema) {
+	f.schema = s
+}
+
+func isDateTime(v interface{}) bool {
+	s, ok := v.(string)
+	if !ok {
+		return true
+	}
+	_, err := time.Parse(time.RFC3339, s)
+	return err == nil
+}
+
+func isDate(v interface{}) bool {
+	s, ok := v.(string)
+	if !ok {
+		return true
+	}
+	_, err := time.Parse("2006-01-02", s)
+	return err == nil
+}
+
+func isTime(v interface{}) bool {
+	s, ok := v.(string)
+	if !ok {
+		return true
+	}
+	_, err := time.Parse("15:04:05Z07:00", s)
+	return err == nil
+}
+
+func isEmail(v interface{}) bool {
+	s, ok := v.(string)
+	if !ok {
+		return true
+	}
+	_, err := mail.ParseAddress(s)
+	return err == nil
+}
+
+var hostnameRegexp = regexp.MustCompile(`^[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(?:\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$`)
+
+func isHostname(v interface{}) bool {
+	s, ok := v.(string)
+	if !ok {
+		return true
+	}
+	return len(s) <= 255 && hostnameRegexp.MatchString(s)
+}
+
+func isIPv4(v interface{}) bool {
+	s, ok := v.(string)
+	if !ok {
+		return true
+	}
+	ip := net.ParseIP(s)
+	return ip != nil && ip.To4() != nil
+}
+
+func isIPv6(v interface{}) bool {
+	s, ok := v.(string)
+	if !ok {
+		return true
+	}
+	ip := net.ParseIP(s)
+	return ip != nil && ip.To4() == nil
+}
+
+func isURI(v interface{}) bool {
+	s, ok := v.(string)
+	if !ok {
+		return true
+	}
+	u, err := url.Parse(s)
+	return err == nil && u.IsAbs()
+}
+
+func isURIReference(v interface{}) bool {
+	s, ok := v.(string)
+	if !ok {
+		return true
+	}
+	_, err := url.Parse(s)
+	return err == nil
+}
+
+var uuidRegexp = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+func isUUID(v interface{}) bool {
+	s, ok := v.(string)
+	if !ok {
+		return true
+	}
+	return uuidRegexp.MatchString(s)
+}
+
+func isRegex(v interface{}) bool {
+	s, ok := v.(string)
+	if !ok {
+		return true
+	}
+	_, err := regexp.Compile(s)
+	return err == nil
+}
+
+func isDuration(v interface{}) bool {
+	s, ok := v.(string)
+	if !ok {
+		return true
+	}
+	_, err := time.ParseDuration(s)
+	return err == nil
+}
@@ -0,0 +1,205 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LintResult is one instance file's validation outcome, as produced by Lint.
+type LintResult struct {
+	Path   string
+	Errors []ValidationError
+	// Lines maps a ValidationError.Field JSON Pointer to the source line it
+	// came from, for instances decoded from YAML (which preserves node
+	// positions). It's nil for JSON instances, since encoding/json discards
+	// position information; callers needing a line number for those should
+	// fall back to line 1 or reparse with a position-tracking JSON decoder.
+	Lines map[string]int
+}
+
+// Lint validates every instance found at instancePaths (files, or
+// directories walked recursively for .json/.yaml/.yml files) against the
+// schema at schemaPath. It's the engine behind cmd/jsonschema-lint, exported
+// so callers can embed the same behavior without shelling out.
+func Lint(schemaPath string, instancePaths []string) ([]LintResult, error) {
+	schemaFile, err := os.Open(schemaPath)
+	if err != nil {
+		return nil, fmt.Errorf("opening schema %s: %w", schemaPath, err)
+	}
+	defer schemaFile.Close()
+
+	schema, err := Parse(schemaFile, true)
+	if err != nil {
+		return nil, fmt.Errorf("parsing schema %s: %w", schemaPath, err)
+	}
+
+	var files []string
+	for _, p := range instancePaths {
+		found, err := collectInstanceFiles(p)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, found...)
+	}
+
+	results := make([]LintResult, 0, len(files))
+	for _, f := range files {
+		instance, lines, err := decodeInstanceFile(f)
+		if err != nil {
+			results = append(results, LintResult{
+				Path:   f,
+				Errors: []ValidationError{{Type: "parse_error", Description: err.Error()}},
+			})
+			continue
+		}
+		results = append(results, LintResult{
+			Path:   f,
+			Errors: schema.Validate(instance, newValidationContext(schema.locale)),
+			Lines:  lines,
+		})
+	}
+	return results, nil
+}
+
+// collectInstanceFiles expands path into the set of .json/.yaml/.yml files
+// to lint: itself if it's a file, or every such file beneath it if it's a
+// directory.
+func collectInstanceFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	var files []string
+	err = filepath.Walk(path, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		if isInstanceFile(p) {
+			files = append(files, p)
+		}
+		return nil
+	})
+	return files, err
+}
+
+func isInstanceFile(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json", ".yaml", ".yml":
+		return true
+	}
+	return false
+}
+
+// decodeInstanceFile parses path as JSON or YAML, based on its extension,
+// into a map[string]interface{} tree that the existing validators already
+// know how to walk. The returned map, when non-nil, gives the source line
+// for each JSON Pointer in the document (YAML only; see LintResult.Lines).
+func decodeInstanceFile(path string) (interface{}, map[string]int, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if strings.ToLower(filepath.Ext(path)) == ".json" {
+		var v interface{}
+		if err := json.Unmarshal(b, &v); err != nil {
+			return nil, nil, err
+		}
+		return v, nil, nil
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return nil, nil, err
+	}
+	var v interface{}
+	if err := doc.Decode(&v); err != nil {
+		return nil, nil, err
+	}
+	v = normalizeYAML(v)
+
+	lines := make(map[string]int)
+	recordYAMLLines(&doc, "", lines)
+	return v, lines, nil
+}
+
+// recordYAMLLines walks a yaml.v3 node tree in lockstep with the JSON
+// Pointers decodeInstanceFile's validated value tree will produce, recording
+// each pointer's source line.
+func recordYAMLLines(n *yaml.Node, pointer string, lines map[string]int) {
+	if n == nil {
+		return
+	}
+	if n.Kind == yaml.DocumentNode {
+		if len(n.Content) > 0 {
+			recordYAMLLines(n.Content[0], pointer, lines)
+		}
+		return
+	}
+
+	lines[pointer] = n.Line
+
+	switch n.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key, val := n.Content[i], n.Content[i+1]
+			recordYAMLLines(val, pointer+"/"+escapePointerToken(key.Value), lines)
+		}
+	case yaml.SequenceNode:
+		for i, item := range n.Content {
+			recordYAMLLines(item, pointer+"/"+strconv.Itoa(i), lines)
+		}
+	}
+}
+
+// normalizeYAML recursively converts map[string]interface{} results from
+// yaml.v3 (and the map[interface{}]interface{} that older decode paths can
+// still produce) into plain map[string]interface{}/[]interface{}, so
+// "properties", "patternProperties", etc. see the same shapes they'd see
+// from encoding/json. It also folds yaml.v3's native int/int64/uint64
+// scalars down to float64, matching encoding/json's number representation,
+// so a "const"/"enum" check against a YAML instance behaves the same as
+// against the equivalent JSON one instead of failing on type mismatch.
+func normalizeYAML(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(val))
+		for k, e := range val {
+			m[k] = normalizeYAML(e)
+		}
+		return m
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(val))
+		for k, e := range val {
+			m[fmt.Sprintf("%v", k)] = normalizeYAML(e)
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(val))
+		for i, e := range val {
+			s[i] = normalizeYAML(e)
+		}
+		return s
+	case int:
+		return float64(val)
+	case int64:
+		return float64(val)
+	case uint64:
+		return float64(val)
+	default:
+		return val
+	}
+}
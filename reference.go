@@ -4,12 +4,16 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
 )
 
+// ErrCyclicRef is returned when resolving a "$ref" would recurse forever,
+// i.e. the ref eventually points back to a schema that is already being
+// resolved.
+var ErrCyclicRef = errors.New("jsonschema: cyclic $ref")
+
 // Using a pointer allows us to handle recursive embedded schemas.
 type EmbeddedSchemas map[string]*Schema
 
@@ -56,38 +60,57 @@ func (e *EmbeddedSchemas) UnmarshalSingle(b []byte) error {
 }
 
 // resolveRefs starts a depth-first search through a document for schemas containing
-// the 'ref' validator. It completely resolves each one found.
-func (s *Schema) resolveRefs(loadExternal bool) {
-	s.resolveSelfAndBelow(*s, loadExternal)
+// the 'ref' validator. It completely resolves each one found, stopping with
+// ErrCyclicRef if a ref ever points back to a schema that is still being resolved.
+func (s *Schema) resolveRefs(loadExternal bool) error {
+	return s.resolveSelfAndBelow(*s, "", loadExternal, make(map[string]bool))
 }
 
-func (s *Schema) resolveSelfAndBelow(rootSchema Schema, loadExternal bool) {
-	s.resolveSelf(rootSchema, loadExternal)
-	s.resolveBelow(rootSchema, loadExternal)
+func (s *Schema) resolveSelfAndBelow(rootSchema Schema, base string, loadExternal bool, visited map[string]bool) error {
+	base = resolveBaseURI(base, s.ID)
+	if err := s.resolveSelf(rootSchema, base, loadExternal, visited); err != nil {
+		return err
+	}
+	return s.resolveBelow(rootSchema, base, loadExternal, visited)
 }
 
-func (s *Schema) resolveSelf(rootSchema Schema, loadExternal bool) {
-	if str, ok := s.hasRef(); ok {
-		sch, err := refToSchema(str, rootSchema, loadExternal)
-		if err != nil {
-			return
-		}
-		*s = *sch
-		s.resolveSelf(rootSchema, loadExternal)
+func (s *Schema) resolveSelf(rootSchema Schema, base string, loadExternal bool, visited map[string]bool) error {
+	str, ok := s.hasRef()
+	if !ok {
+		return nil
+	}
+	absRef := resolveAgainstBase(base, str)
+	if visited[absRef] {
+		return ErrCyclicRef
 	}
+	// Only track absRef for the duration of this resolution chain: once it's
+	// fully resolved below, the same ref reached from a sibling branch (the
+	// common "two properties pointing at one shared definition" case) is not
+	// a cycle, just reuse.
+	visited[absRef] = true
+	defer delete(visited, absRef)
+
+	sch, err := refToSchema(absRef, rootSchema, loadExternal)
+	if err != nil {
+		return nil
+	}
+	*s = *sch
+	return s.resolveSelf(rootSchema, base, loadExternal, visited)
 }
 
-// TODO: test that we fail gracefully if the schema contains infinitely looping "$ref"s.
-func (s *Schema) resolveBelow(rootSchema Schema, loadExternal bool) {
+func (s *Schema) resolveBelow(rootSchema Schema, base string, loadExternal bool, visited map[string]bool) error {
 	if s.resolved == true {
-		return
+		return nil
 	}
 	s.resolved = true
 	for _, n := range s.nodes {
 		for _, sch := range n.EmbeddedSchemas {
-			sch.resolveSelfAndBelow(rootSchema, loadExternal)
+			if err := sch.resolveSelfAndBelow(rootSchema, base, loadExternal, visited); err != nil {
+				return err
+			}
 		}
 	}
+	return nil
 }
 
 func (s *Schema) hasRef() (string, bool) {
@@ -99,73 +122,135 @@ func (s *Schema) hasRef() (string, bool) {
 	return "", false
 }
 
-// TODO: This is hacky. Look into using a library like gojsonpointer[1] instead.
-//
-// [1] https://github.com/xeipuuv/gojsonpointer
+// resolveBaseURI folds a subschema's "$id" into the base URI inherited from
+// its parent, per the draft-07 "$id" resolution scope rules. A relative
+// "$id" is resolved against the parent base the same way a relative "$ref"
+// would be.
+func resolveBaseURI(base, id string) string {
+	if id == "" {
+		return base
+	}
+	if base == "" {
+		return id
+	}
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return id
+	}
+	idURL, err := url.Parse(id)
+	if err != nil {
+		return base
+	}
+	return baseURL.ResolveReference(idURL).String()
+}
+
+// resolveAgainstBase resolves a (possibly relative) "$ref" against the
+// nearest enclosing base URI, so that refs inside a nested document with its
+// own "$id" resolve relative to that "$id" rather than the root document.
+func resolveAgainstBase(base, ref string) string {
+	if base == "" {
+		return ref
+	}
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return ref
+	}
+	refURL, err := url.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return baseURL.ResolveReference(refURL).String()
+}
+
+// Resolve looks up the schema at the given RFC 6901 JSON Pointer, e.g.
+// "#/definitions/foo" or "allOf/0/properties/bar". It lets callers walk a
+// schema document the same way "$ref" resolution does, without needing a
+// ref to trigger it.
+func (s *Schema) Resolve(pointer string) (*Schema, error) {
+	return refToSchema(pointer, *s, false)
+}
+
 func refToSchema(str string, rootSchema Schema, loadExternal bool) (*Schema, error) {
-	var split []string
-	url, err := url.Parse(str)
-	if err == nil && url.IsAbs() {
-		cacheKey := strings.TrimSuffix(str, url.Fragment)
-		cachedSchema, ok := rootSchema.Cache[cacheKey]
-		if ok {
+	u, err := url.Parse(str)
+	if err == nil && u.IsAbs() {
+		cacheKey := strings.TrimSuffix(str, "#"+u.Fragment)
+		if rootSchema.Cache == nil {
+			rootSchema.Cache = newSchemaCache()
+		}
+		if cachedSchema, ok := rootSchema.Cache.get(cacheKey); ok {
 			rootSchema = *cachedSchema
 		} else {
-			// Handle external URIs.
-			if !loadExternal {
-				return new(Schema), errors.New("external schemas are disabled")
-			}
-			resp, err := http.Get(str)
-			if err != nil {
-				return new(Schema), errors.New("bad external url")
-			}
-			defer resp.Body.Close()
-			s, err := ParseWithCache(resp.Body, loadExternal, &rootSchema.Cache)
+			sch, err := loadExternalSchema(cacheKey, rootSchema, loadExternal)
 			if err != nil {
-				return new(Schema), errors.New("error parsing external doc")
+				return new(Schema), err
 			}
-			rootSchema.Cache[cacheKey] = s
-			rootSchema = *s
+			rootSchema.Cache.set(cacheKey, sch)
+			rootSchema = *sch
 		}
-		str = url.Fragment
+		str = u.Fragment
 	}
 
-	// Remove the prefix from internal URIs.
+	return resolvePointer(str, rootSchema)
+}
+
+// resolvePointer resolves an RFC 6901 JSON Pointer (with or without the
+// leading "#") against rootSchema, descending through EmbeddedSchemas one
+// pointer segment at a time so that refs of arbitrary depth (e.g.
+// "#/allOf/0/properties/bar") resolve correctly.
+func resolvePointer(str string, rootSchema Schema) (*Schema, error) {
+	orig := str
 	str = strings.TrimPrefix(str, "#")
+	if str == "" {
+		return &rootSchema, nil
+	}
 	str = strings.TrimPrefix(str, "/")
 
-	split = strings.Split(str, "/")
-	// Make replacements.
-	for i, v := range split {
-		r := strings.NewReplacer("~0", "~", "~1", "/", "%25", "%")
-		split[i] = r.Replace(v)
+	tokens := strings.Split(str, "/")
+	for i, tok := range tokens {
+		tokens[i] = unescapeToken(tok)
 	}
-	// Resolve the local part of the URI.
-	return resolveLocalPath(split, rootSchema, str)
+	return walkPointer(tokens, &rootSchema, orig)
 }
 
-// TODO: add code and tests for references more than one level deep.
-func resolveLocalPath(split []string, rootSchema Schema, str string) (*Schema, error) {
-	switch len(split) {
-	case 1:
-		if split[0] == "" {
-			return &rootSchema, nil
-		}
-		v, ok := rootSchema.nodes[split[0]]
-		if ok == false {
-			break
-		}
-		if s, ok := v.EmbeddedSchemas[""]; ok {
-			return s, nil
-		}
-	case 2:
-		v, ok := rootSchema.nodes[split[0]]
-		if ok == false {
-			break
-		}
-		if s, ok := v.EmbeddedSchemas[split[1]]; ok {
-			return s, nil
-		}
+// unescapeToken decodes the RFC 6901 escape sequences in a single pointer
+// segment. The order matters: "~1" must be decoded before "~0", otherwise
+// "~01" would wrongly become "/" instead of "~1".
+func unescapeToken(tok string) string {
+	tok = strings.ReplaceAll(tok, "~1", "/")
+	tok = strings.ReplaceAll(tok, "~0", "~")
+	return tok
+}
+
+// walkPointer descends into cur one pointer token at a time, consuming a
+// second token per hop for keywords that own a set of several EmbeddedSchemas
+// keyed by object property name or, for array keywords, the decimal string
+// index (e.g. "definitions/foo", "allOf/0"). Keywords that hold exactly one
+// embedded schema (e.g. "not", "if", "then", "propertyNames", the
+// single-schema form of "items"/"additionalProperties") store it under the
+// "" key instead, and only the keyword token itself is consumed at that hop
+// — this must be checked at every hop, not just the last one, so pointers
+// like "#/allOf/0/not/properties/x" or "#/then/properties/x" resolve too.
+func walkPointer(tokens []string, cur *Schema, orig string) (*Schema, error) {
+	if len(tokens) == 0 {
+		return cur, nil
+	}
+
+	n, ok := cur.nodes[tokens[0]]
+	if !ok {
+		return new(Schema), fmt.Errorf("failed to resolve %s: no keyword %q", orig, tokens[0])
+	}
+
+	if s, ok := n.EmbeddedSchemas[""]; ok {
+		return walkPointer(tokens[1:], s, orig)
+	}
+
+	if len(tokens) == 1 {
+		return new(Schema), fmt.Errorf("failed to resolve %s: keyword %q has no embedded schema", orig, tokens[0])
+	}
+
+	next, ok := n.EmbeddedSchemas[tokens[1]]
+	if !ok {
+		return new(Schema), fmt.Errorf("failed to resolve %s: no embedded schema %q under %q", orig, tokens[1], tokens[0])
 	}
-	return new(Schema), fmt.Errorf("failed to resolve %s", str)
+	return walkPointer(tokens[2:], next, orig)
 }
@@ -0,0 +1,137 @@
+package jsonschema
+
+import "testing"
+
+func TestUnescapeToken(t *testing.T) {
+	cases := []struct {
+		in   string
+		want string
+	}{
+		{"definitions", "definitions"},
+		{"a~1b", "a/b"},
+		{"a~0b", "a~b"},
+		// "~01" must decode to "~1", not "/": ~1 is decoded before ~0, so the
+		// "~1" produced by decoding "~01"'s "~0" prefix is never re-decoded
+		// into "/".
+		{"~01", "~1"},
+	}
+	for _, c := range cases {
+		if got := unescapeToken(c.in); got != c.want {
+			t.Errorf("unescapeToken(%q) = %q, want %q", c.in, got, c.want)
+		}
+	}
+}
+
+func TestEscapePointerTokenRoundTrip(t *testing.T) {
+	cases := []string{"definitions", "a/b", "a~b", "~1"}
+	for _, c := range cases {
+		escaped := escapePointerToken(c)
+		if got := unescapeToken(escaped); got != c {
+			t.Errorf("round trip of %q via %q = %q, want %q", c, escaped, got, c)
+		}
+	}
+}
+
+func TestResolveBaseURI(t *testing.T) {
+	cases := []struct {
+		base, id, want string
+	}{
+		{"", "", ""},
+		{"", "http://example.com/a.json", "http://example.com/a.json"},
+		{"http://example.com/a.json", "", "http://example.com/a.json"},
+		{"http://example.com/dir/a.json", "b.json", "http://example.com/dir/b.json"},
+	}
+	for _, c := range cases {
+		if got := resolveBaseURI(c.base, c.id); got != c.want {
+			t.Errorf("resolveBaseURI(%q, %q) = %q, want %q", c.base, c.id, got, c.want)
+		}
+	}
+}
+
+func TestResolveAgainstBase(t *testing.T) {
+	cases := []struct {
+		base, ref, want string
+	}{
+		{"", "#/definitions/foo", "#/definitions/foo"},
+		{"http://example.com/dir/a.json", "#/definitions/foo", "http://example.com/dir/a.json#/definitions/foo"},
+		{"http://example.com/dir/a.json", "other.json#/definitions/foo", "http://example.com/dir/other.json#/definitions/foo"},
+	}
+	for _, c := range cases {
+		if got := resolveAgainstBase(c.base, c.ref); got != c.want {
+			t.Errorf("resolveAgainstBase(%q, %q) = %q, want %q", c.base, c.ref, got, c.want)
+		}
+	}
+}
+
+// TestWalkPointerDeep covers arbitrary-depth descent through nested
+// EmbeddedSchemas, including keywords that store a single embedded schema
+// under the "" key mid-pointer rather than only at the end (e.g. "not"
+// sitting between two multi-schema hops).
+func TestWalkPointerDeep(t *testing.T) {
+	leaf := &Schema{}
+	notTarget := &Schema{
+		nodes: map[string]*node{
+			"properties": {EmbeddedSchemas: EmbeddedSchemas{"x": leaf}},
+		},
+	}
+	root := &Schema{
+		nodes: map[string]*node{
+			"allOf": {EmbeddedSchemas: EmbeddedSchemas{
+				"0": {
+					nodes: map[string]*node{
+						"not": {EmbeddedSchemas: EmbeddedSchemas{"": notTarget}},
+					},
+				},
+			}},
+		},
+	}
+
+	got, err := walkPointer([]string{"allOf", "0", "not", "properties", "x"}, root, "#/allOf/0/not/properties/x")
+	if err != nil {
+		t.Fatalf("walkPointer returned error: %v", err)
+	}
+	if got != leaf {
+		t.Fatalf("walkPointer did not resolve to the expected leaf schema")
+	}
+}
+
+// TestResolveRefsDiamondIsNotCyclic is a regression test: the same schema
+// reached via two different "$ref"s (a diamond, not a cycle) must resolve
+// cleanly regardless of which branch the DFS visits first.
+func TestResolveRefsDiamondIsNotCyclic(t *testing.T) {
+	address := &Schema{}
+	billingRef := ref("#/definitions/address")
+	shippingRef := ref("#/definitions/address")
+	billing := &Schema{nodes: map[string]*node{"$ref": {Validator: &billingRef}}}
+	shipping := &Schema{nodes: map[string]*node{"$ref": {Validator: &shippingRef}}}
+
+	root := &Schema{
+		nodes: map[string]*node{
+			"definitions": {EmbeddedSchemas: EmbeddedSchemas{"address": address}},
+			"properties":  {EmbeddedSchemas: EmbeddedSchemas{"billing": billing, "shipping": shipping}},
+		},
+	}
+
+	if err := root.resolveRefs(false); err != nil {
+		t.Fatalf("resolveRefs on a diamond-shaped (non-cyclic) document returned %v, want nil", err)
+	}
+}
+
+// TestResolveRefsDetectsCycle checks that a genuine mutual "$ref" cycle is
+// still reported, rather than the diamond fix silently swallowing it.
+func TestResolveRefsDetectsCycle(t *testing.T) {
+	refB := ref("#/definitions/b")
+	refA := ref("#/definitions/a")
+	a := &Schema{nodes: map[string]*node{"$ref": {Validator: &refB}}}
+	b := &Schema{nodes: map[string]*node{"$ref": {Validator: &refA}}}
+
+	root := &Schema{
+		nodes: map[string]*node{
+			"definitions": {EmbeddedSchemas: EmbeddedSchemas{"a": a, "b": b}},
+		},
+	}
+
+	if err := root.resolveRefs(false); err != ErrCyclicRef {
+		t.Fatalf("resolveRefs on a cyclic document returned %v, want ErrCyclicRef", err)
+	}
+}
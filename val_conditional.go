@@ -0,0 +1,121 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// constValidator is the "const" keyword: the instance must deep-equal a
+// single fixed value. Draft-06+.
+type constValidator struct {
+	value interface{}
+}
+
+func (c constValidator) Validate(v interface{}, ctx *ValidationContext) []ValidationError {
+	if reflect.DeepEqual(v, c.value) {
+		return nil
+	}
+	nested := ctx.descend(nil, []string{"const"})
+	return []ValidationError{nested.newError("const", nested.locale.Const(), map[string]interface{}{"given": v})}
+}
+
+func (c *constValidator) UnmarshalJSON(b []byte) error {
+	return json.Unmarshal(b, &c.value)
+}
+
+// contains requires at least one array item to validate against schema. The
+// neighboring "minContains"/"maxContains" keywords (draft 2019-09) narrow
+// that to a range; minContains defaults to 1, the draft-06 "contains"
+// behavior, only when it's truly absent — "minContains": 0 is a valid,
+// distinct setting (often paired with just "maxContains" to assert an upper
+// bound) that must not be forced back up to 1. Draft-06+.
+type contains struct {
+	schema Schema
+	min    int
+	hasMin bool
+	max    int
+	hasMax bool
+}
+
+func (c contains) Validate(v interface{}, ctx *ValidationContext) []ValidationError {
+	arr, ok := v.([]interface{})
+	if !ok {
+		return nil
+	}
+	nested := ctx.descend(nil, []string{"contains"})
+	matched := 0
+	for _, item := range arr {
+		if len(c.schema.Validate(item, nested)) == 0 {
+			matched++
+		}
+	}
+
+	min := 1
+	if c.hasMin {
+		min = c.min
+	}
+
+	var valErrs []ValidationError
+	if matched < min {
+		valErrs = append(valErrs, nested.newError("contains", nested.locale.Contains(), map[string]interface{}{"min": min, "given": matched}))
+	}
+	if c.hasMax && matched > c.max {
+		valErrs = append(valErrs, nested.newError("max_contains", nested.locale.MaxContains(), map[string]interface{}{"max": c.max, "given": matched}))
+	}
+	return valErrs
+}
+
+func (c *contains) UnmarshalJSON(b []byte) error {
+	return json.Unmarshal(b, &c.schema)
+}
+
+func (c *contains) SetSchema(v map[string]json.RawMessage) error {
+	if val, ok := v["minContains"]; ok {
+		c.hasMin = true
+		json.Unmarshal(val, &c.min)
+	}
+	if val, ok := v["maxContains"]; ok {
+		c.hasMax = true
+		json.Unmarshal(val, &c.max)
+	}
+	return nil
+}
+
+// ifThenElse implements the "if"/"then"/"else" conditional: "if" is
+// evaluated for validity only (its errors are discarded), and "then" or
+// "else" is applied depending on the outcome. Draft-07+.
+type ifThenElse struct {
+	ifSchema   *Schema
+	thenSchema *Schema
+	elseSchema *Schema
+}
+
+func (c ifThenElse) Validate(v interface{}, ctx *ValidationContext) []ValidationError {
+	if c.ifSchema == nil {
+		return nil
+	}
+	if len(c.ifSchema.Validate(v, ctx.descend(nil, []string{"if"}))) == 0 {
+		if c.thenSchema == nil {
+			return nil
+		}
+		return c.thenSchema.Validate(v, ctx.descend(nil, []string{"then"}))
+	}
+	if c.elseSchema == nil {
+		return nil
+	}
+	return c.elseSchema.Validate(v, ctx.descend(nil, []string{"else"}))
+}
+
+func (c *ifThenElse) UnmarshalJSON(b []byte) error {
+	return json.Unmarshal(b, &c.ifSchema)
+}
+
+func (c *ifThenElse) SetSchema(v map[string]json.RawMessage) error {
+	if val, ok := v["then"]; ok {
+		json.Unmarshal(val, &c.thenSchema)
+	}
+	if val, ok := v["else"]; ok {
+		json.Unmarshal(val, &c.elseSchema)
+	}
+	return nil
+}
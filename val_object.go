@@ -3,7 +3,6 @@ package jsonschema
 import (
 	"encoding/json"
 	"errors"
-	"fmt"
 	"regexp"
 )
 
@@ -14,34 +13,25 @@ type dependencies struct {
 
 type propertySet map[string]struct{}
 
-func (d dependencies) Validate(v interface{}) []ValidationError {
+// Validate delegates to dependentSchemas and dependentRequired, the draft
+// 2019-09 keywords that supersede the overloaded "dependencies" keyword. It's
+// kept around only so documents still using "dependencies" keep validating.
+func (d dependencies) Validate(v interface{}, ctx *ValidationContext) []ValidationError {
 	var valErrs []ValidationError
-	val, ok := v.(map[string]interface{})
-	if !ok {
-		return nil
-	}
-
-	// Handle schema dependencies.
-	for key, schema := range d.schemaDeps {
-		if _, ok := val[key]; !ok {
-			continue
-		}
-		valErrs = append(valErrs, schema.Validate(v)...)
-	}
-
-	// Handle property dependencies.
-	for key, set := range d.propertyDeps {
-		if _, ok := val[key]; !ok {
-			continue
-		}
-		for a := range set {
-			if _, ok := val[a]; !ok {
-				valErrs = append(valErrs, ValidationError{
-					fmt.Sprintf("instance does not have a property with the name %s", a)})
+	valErrs = append(valErrs, dependentSchemas(d.schemaDeps).Validate(v, ctx)...)
+	valErrs = append(valErrs, dependentRequired(d.propertyDeps).Validate(v, ctx)...)
+	// The delegates anchor SchemaField at their own keywords, which this
+	// schema doesn't contain; point those errors back at "dependencies".
+	base := ctx.schemaFieldPointer()
+	for i, e := range valErrs {
+		for _, kw := range []string{"/dependentSchemas", "/dependentRequired"} {
+			p := base + kw
+			if len(e.SchemaField) >= len(p) && e.SchemaField[:len(p)] == p {
+				valErrs[i].SchemaField = base + "/dependencies" + e.SchemaField[len(p):]
+				break
 			}
 		}
 	}
-
 	return valErrs
 }
 
@@ -81,14 +71,15 @@ func (d *dependencies) UnmarshalJSON(b []byte) error {
 
 type maxProperties int
 
-func (m maxProperties) Validate(v interface{}) []ValidationError {
+func (m maxProperties) Validate(v interface{}, ctx *ValidationContext) []ValidationError {
 	val, ok := v.(map[string]interface{})
 	if !ok {
 		return nil
 	}
 	if len(val) > int(m) {
-		return []ValidationError{ValidationError{
-			fmt.Sprintf("Object has more properties than maxProperties (%d > %d)", len(val), m)}}
+		nested := ctx.descend(nil, []string{"maxProperties"})
+		return []ValidationError{nested.newError("max_properties", nested.locale.MaxProperties(),
+			map[string]interface{}{"given": len(val), "max": int(m)})}
 	}
 	return nil
 }
@@ -107,14 +98,15 @@ func (m *maxProperties) UnmarshalJSON(b []byte) error {
 
 type minProperties int
 
-func (m minProperties) Validate(v interface{}) []ValidationError {
+func (m minProperties) Validate(v interface{}, ctx *ValidationContext) []ValidationError {
 	val, ok := v.(map[string]interface{})
 	if !ok {
 		return nil
 	}
 	if len(val) < int(m) {
-		return []ValidationError{ValidationError{
-			fmt.Sprintf("Object has fewer properties than minProperties (%d < %d)", len(val), m)}}
+		nested := ctx.descend(nil, []string{"minProperties"})
+		return []ValidationError{nested.newError("min_properties", nested.locale.MinProperties(),
+			map[string]interface{}{"given": len(val), "min": int(m)})}
 	}
 	return nil
 }
@@ -140,7 +132,7 @@ type regexpToSchema struct {
 	schema Schema
 }
 
-func (p patternProperties) Validate(v interface{}) []ValidationError {
+func (p patternProperties) Validate(v interface{}, ctx *ValidationContext) []ValidationError {
 	var valErrs []ValidationError
 	data, ok := v.(map[string]interface{})
 	if !ok {
@@ -149,7 +141,8 @@ func (p patternProperties) Validate(v interface{}) []ValidationError {
 	for dataKey, dataVal := range data {
 		for _, val := range p.object {
 			if val.regexp.MatchString(dataKey) {
-				valErrs = append(valErrs, val.schema.Validate(dataVal)...)
+				nested := ctx.descend([]string{dataKey}, []string{"patternProperties", val.regexp.String()})
+				valErrs = append(valErrs, val.schema.Validate(dataVal, nested)...)
 			}
 		}
 	}
@@ -185,7 +178,7 @@ type properties struct {
 	additionalPropertiesObject *Schema
 }
 
-func (p properties) Validate(v interface{}) []ValidationError {
+func (p properties) Validate(v interface{}, ctx *ValidationContext) []ValidationError {
 	var valErrs []ValidationError
 	dataMap, ok := v.(map[string]interface{})
 	if !ok {
@@ -195,13 +188,15 @@ func (p properties) Validate(v interface{}) []ValidationError {
 		var match = false
 		schema, ok := p.object[dataKey]
 		if ok {
-			valErrs = append(valErrs, schema.Validate(dataVal)...)
+			nested := ctx.descend([]string{dataKey}, []string{"properties", dataKey})
+			valErrs = append(valErrs, schema.Validate(dataVal, nested)...)
 			match = true
 		}
 		if p.patternProperties != nil {
 			for _, val := range p.patternProperties.object {
 				if val.regexp.MatchString(dataKey) {
-					valErrs = append(valErrs, val.schema.Validate(dataVal)...)
+					nested := ctx.descend([]string{dataKey}, []string{"patternProperties", val.regexp.String()})
+					valErrs = append(valErrs, val.schema.Validate(dataVal, nested)...)
 					match = true
 				}
 			}
@@ -209,12 +204,14 @@ func (p properties) Validate(v interface{}) []ValidationError {
 		if match {
 			continue
 		}
+		nested := ctx.descend([]string{dataKey}, []string{"additionalProperties"})
 		if p.additionalPropertiesObject != nil {
-			valErrs = append(valErrs, p.additionalPropertiesObject.Validate(dataVal)...)
+			valErrs = append(valErrs, p.additionalPropertiesObject.Validate(dataVal, nested)...)
 			continue
 		}
 		if !p.additionalPropertiesBool {
-			valErrs = append([]ValidationError{ValidationError{"Additional properties aren't allowed"}})
+			valErrs = append(valErrs, nested.newError("additional_property_not_allowed", nested.locale.AdditionalPropertyNotAllowed(),
+				map[string]interface{}{"property": dataKey}))
 		}
 	}
 	return valErrs
@@ -243,15 +240,16 @@ func (p *properties) SetSchema(v map[string]json.RawMessage) error {
 
 type required map[string]struct{}
 
-func (r required) Validate(v interface{}) []ValidationError {
+func (r required) Validate(v interface{}, ctx *ValidationContext) []ValidationError {
 	var valErrs []ValidationError
 	data, ok := v.(map[string]interface{})
 	if !ok {
 		return nil
 	}
+	nested := ctx.descend(nil, []string{"required"})
 	for key := range r {
 		if _, ok := data[key]; !ok {
-			valErrs = append(valErrs, ValidationError{fmt.Sprintf("Required error. The data must be an object with %v as one of its keys", key)})
+			valErrs = append(valErrs, nested.newError("required", nested.locale.Required(), map[string]interface{}{"property": key}))
 		}
 	}
 	return valErrs
@@ -268,3 +266,92 @@ func (r *required) UnmarshalJSON(b []byte) error {
 	}
 	return nil
 }
+
+// propertyNames validates each property *name* of an object, rather than its
+// value, against a subschema. Draft-06+.
+type propertyNames struct {
+	schema Schema
+}
+
+func (p propertyNames) Validate(v interface{}, ctx *ValidationContext) []ValidationError {
+	data, ok := v.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	var valErrs []ValidationError
+	nested := ctx.descend(nil, []string{"propertyNames"})
+	for key := range data {
+		valErrs = append(valErrs, p.schema.Validate(key, nested)...)
+	}
+	return valErrs
+}
+
+func (p *propertyNames) UnmarshalJSON(b []byte) error {
+	return json.Unmarshal(b, &p.schema)
+}
+
+// dependentRequired is the draft 2019-09 replacement for the property-set half of
+// "dependencies": if key is present, every property in its set must be too.
+type dependentRequired map[string]propertySet
+
+func (d dependentRequired) Validate(v interface{}, ctx *ValidationContext) []ValidationError {
+	data, ok := v.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	var valErrs []ValidationError
+	for key, set := range d {
+		if _, ok := data[key]; !ok {
+			continue
+		}
+		nested := ctx.descend(nil, []string{"dependentRequired", key})
+		for a := range set {
+			if _, ok := data[a]; !ok {
+				valErrs = append(valErrs, nested.newError("dependency_required_property", nested.locale.DependencyRequiredProperty(),
+					map[string]interface{}{"property": a, "dependency": key}))
+			}
+		}
+	}
+	return valErrs
+}
+
+func (d *dependentRequired) UnmarshalJSON(b []byte) error {
+	var m map[string][]string
+	if err := json.Unmarshal(b, &m); err != nil {
+		return err
+	}
+	*d = make(dependentRequired, len(m))
+	for key, props := range m {
+		set := make(propertySet, len(props))
+		for _, p := range props {
+			set[p] = struct{}{}
+		}
+		(*d)[key] = set
+	}
+	return nil
+}
+
+// dependentSchemas is the draft 2019-09 replacement for the schema half of
+// "dependencies": if key is present, the instance must also validate against
+// its subschema.
+type dependentSchemas map[string]Schema
+
+func (d dependentSchemas) Validate(v interface{}, ctx *ValidationContext) []ValidationError {
+	data, ok := v.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	var valErrs []ValidationError
+	for key, schema := range d {
+		if _, ok := data[key]; !ok {
+			continue
+		}
+		nested := ctx.descend(nil, []string{"dependentSchemas", key})
+		valErrs = append(valErrs, schema.Validate(v, nested)...)
+	}
+	return valErrs
+}
+
+func (d *dependentSchemas) UnmarshalJSON(b []byte) error {
+	return json.Unmarshal(b, (*map[string]Schema)(d))
+}